@@ -51,3 +51,50 @@ func assertParseFails(encoding []byte, allowU64 bool) {
 		panic(fmt.Sprintf("parseCompactSize(%v) failed to return an error", encoding))
 	}
 }
+
+// FuzzCompactSizeRoundtrip asserts that ParseCompactSize(WriteCompactSize(n)) == n
+// for every allowed uint64, and that ParseCompactSize never panics on arbitrary
+// input bytes.
+func FuzzCompactSizeRoundtrip(f *testing.F) {
+	for _, n := range []uint64{
+		0, 1, 252, 253, 254, 255, 256,
+		0xFFFE, 0xFFFF, 0x010000, 0x010001,
+		0xFFFFFFFE, 0xFFFFFFFF, 0x0100000000,
+		0xFFFFFFFFFFFFFFFF,
+	} {
+		f.Add(n, []byte{})
+	}
+	// Regression seeds for historical edge cases: oversized length markers and
+	// truncated multi-byte encodings.
+	f.Add(uint64(0), []byte{0xFE, 0x01, 0x00, 0x00, 0x02})
+	f.Add(uint64(0), []byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00})
+	f.Add(uint64(0), []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+	f.Add(uint64(0), []byte{0xFD})
+	f.Add(uint64(0), []byte{})
+
+	f.Fuzz(func(t *testing.T, n uint64, arbitrary []byte) {
+		encoded, err := WriteCompactSize(n, true)
+		if err != nil {
+			t.Fatalf("WriteCompactSize(%d) failed: %v", n, err)
+		}
+		parsed, remaining, err := ParseCompactSize(encoded, true)
+		if err != nil {
+			t.Fatalf("ParseCompactSize failed to parse its own encoding of %d: %v", n, err)
+		}
+		if parsed != n {
+			t.Fatalf("roundtrip mismatch: wrote %d, parsed %d", n, parsed)
+		}
+		if len(remaining) != 0 {
+			t.Fatalf("ParseCompactSize did not consume the entirety of its own encoding of %d", n)
+		}
+
+		// ParseCompactSize must never panic, regardless of how malformed its
+		// input is.
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("ParseCompactSize panicked on input %x: %v", arbitrary, r)
+			}
+		}()
+		ParseCompactSize(arbitrary, true)
+	})
+}
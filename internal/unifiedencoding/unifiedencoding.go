@@ -0,0 +1,119 @@
+// package unifiedencoding
+//
+// Shared TLV, padding, F4Jumble and bech32m plumbing used by both the
+// unifiedaddress and unifiedviewingkey packages to encode and decode the
+// ZIP 316 item-list framing common to Unified Addresses, Unified Full Viewing
+// Keys and Unified Incoming Viewing Keys.
+package unifiedencoding
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/MarcoEzekiel/go-f4jumble"
+	"github.com/MarcoEzekiel/go-zcashaddress/internal/compactsize"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+// TLV encodes a single (typecode, value) item as a CompactSize typecode,
+// followed by a CompactSize length, followed by the value bytes.
+func TLV(typecode uint64, value []byte) ([]byte, error) {
+	start, err := compactsize.WriteCompactSize(typecode, true)
+	if err != nil {
+		return nil, err
+	}
+
+	simplified := uint64(len(value))
+	st, err2 := compactsize.WriteCompactSize(simplified, true)
+	if err2 != nil {
+		return nil, err
+	}
+
+	return append(start, append(st, value...)...), nil
+}
+
+// Padding returns the 16-byte HRP padding appended to an item list before
+// F4Jumble, as defined in ZIP 316.
+func Padding(hrp string) []byte {
+	hrpBytes := []byte(hrp)
+	padLength := 16 - len(hrpBytes)
+	if padLength < 0 {
+		padLength = 0
+	}
+	return append(hrpBytes, bytes.Repeat([]byte{0x00}, padLength)...)
+}
+
+// minF4JumbleLen is F4Jumble's minimum input length. A short item list, such
+// as a lone transparent receiver, can fall below this floor once the 16-byte
+// HRP padding is added; Wrap tops it up with trailing zero filler so F4Jumble
+// still accepts it.
+const minF4JumbleLen = 48
+
+// IsZeroFiller reports whether data consists entirely of zero bytes. Callers
+// parsing a TLV item list produced by Wrap use this to recognize the trailing
+// zero filler Wrap adds to meet minF4JumbleLen, rather than attempting to
+// parse it as a spurious item: a genuine TLV item's typecode and length
+// prefix are never both zero, since every encoded item has a non-zero
+// length.
+func IsZeroFiller(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Wrap appends the HRP padding to items, F4Jumbles the result, and encodes it
+// as bech32m with the given human-readable part.
+func Wrap(items []byte, hrp string) (string, error) {
+	padding := Padding(hrp)
+	if shortfall := minF4JumbleLen - len(items) - len(padding); shortfall > 0 {
+		items = append(items, bytes.Repeat([]byte{0x00}, shortfall)...)
+	}
+	padded := append(items, padding...)
+
+	jumbledBytes, err := f4jumble.F4Jumble(padded)
+	if err != nil {
+		return "", err
+	}
+
+	converted, convertErr := bech32.ConvertBits(jumbledBytes, 8, 5, true)
+	if convertErr != nil {
+		return "", convertErr
+	}
+	return bech32.EncodeM(hrp, converted)
+}
+
+// Unwrap decodes a bech32m string against the expected human-readable part,
+// reverses F4Jumble, validates and strips the trailing HRP padding, and
+// returns the remaining item-list bytes.
+func Unwrap(encoded, expectedHrp string, minDataLen int) ([]byte, error) {
+	hrp, data, version, encoding := bech32.DecodeNoLimitWithVersion(encoded)
+
+	if version != bech32.VersionM {
+		return nil, errors.New("expected bech32m encoding")
+	}
+	if hrp != expectedHrp || encoding != nil {
+		return nil, errors.New("invalid HRP or encoding")
+	}
+	if len(data) < minDataLen {
+		return nil, errors.New("invalid encoded data length")
+	}
+
+	convertedBits, convertedBitsErr := bech32.ConvertBits(data, 5, 8, false)
+	if convertedBitsErr != nil {
+		return nil, convertedBitsErr
+	}
+
+	decoded, decodedErr := f4jumble.F4JumbleInv(convertedBits)
+	if decodedErr != nil {
+		return nil, decodedErr
+	}
+
+	suffix := decoded[len(decoded)-16:]
+	if !bytes.Equal(suffix, Padding(expectedHrp)) {
+		return nil, errors.New("invalid trailing padding")
+	}
+	return decoded[:len(decoded)-16], nil
+}
@@ -5,27 +5,92 @@ package unifiedaddress
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"sort"
 
-	"github.com/MarcoEzekiel/go-f4jumble"
 	"github.com/MarcoEzekiel/go-zcashaddress/internal/compactsize"
-	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/MarcoEzekiel/go-zcashaddress/internal/unifiedencoding"
 )
 
-type ItemType uint64
+// Revision identifies a ZIP 316 revision of the Unified Address encoding.
+type Revision int
+
+const (
+	// R0 is the original ZIP 316 encoding: data items only, no metadata items,
+	// and at least one shielded receiver is required.
+	R0 Revision = 0
+	// R1 is the ZIP 316 Revision 1 encoding: permits metadata items to be
+	// appended after the data items, and permits transparent-only addresses.
+	R1 Revision = 1
+)
+
+// DataTypecode identifies a receiver item within a Unified Address, such as a
+// transparent, Sapling or Orchard receiver.
+type DataTypecode uint64
 
 // breaking the sequential order of items NoPreviousItem is a max value for ordering checks
-// in DecodeUnified()git
+// in decodeUnified()
+const (
+	P2PKHItem      DataTypecode = 0x00
+	P2SHItem       DataTypecode = 0x01
+	SaplingItem    DataTypecode = 0x02
+	OrchardItem    DataTypecode = 0x03
+	NoPreviousItem DataTypecode = 0xffffffff
+)
+
+// MetadataTypecode identifies a ZIP 316 Revision 1 metadata item within a Unified
+// Address.
+type MetadataTypecode uint64
+
 const (
-	P2PKHItem      ItemType = 0x00
-	P2SHItem       ItemType = 0x01
-	SaplingItem    ItemType = 0x02
-	OrchardItem    ItemType = 0x03
-	NoPreviousItem ItemType = 0xffffffff
+	// ExpiryHeightItem is the MUST-understand expiry-height metadata item: a
+	// little-endian u32 Zcash block height.
+	ExpiryHeightItem MetadataTypecode = 0xE0
+	// ExpiryTimeItem is the MUST-understand expiry-time metadata item: a
+	// little-endian u64 Unix timestamp in seconds.
+	ExpiryTimeItem MetadataTypecode = 0xE1
 )
 
-func getExpectedLength(itemType ItemType) uint64 {
+// metadataRangeStart is the first typecode reserved for metadata items; typecodes
+// below this value identify data items instead.
+const metadataRangeStart uint64 = 0xC0
+
+// metadataMustUnderstandEnd is the last typecode in the reserved MUST-understand
+// metadata space. A decoder that does not recognize a typecode in
+// metadataRangeStart..metadataMustUnderstandEnd must reject the address; typecodes
+// above metadataMustUnderstandEnd are MAY-understand and may be preserved unparsed.
+const metadataMustUnderstandEnd uint64 = 0xFD
+
+// MetadataItem is a single ZIP 316 Revision 1 metadata item, as returned by
+// UnifiedAddress.MetadataItems.
+type MetadataItem struct {
+	Typecode MetadataTypecode
+	Data     []byte
+}
+
+// ErrTransparentOnlyR0 is returned when a Unified Address contains only a
+// transparent (P2pkh or P2sh) receiver and no shielded receiver, which ZIP 316
+// Revision 0 disallows by default. Revision 1 permits transparent-only
+// addresses; see EncodeUnifiedOpts and DecodeUnifiedOpts.
+var ErrTransparentOnlyR0 = errors.New("unified address revision 0 does not permit a transparent-only receiver set")
+
+// isTransparentOnly reports whether addr has a transparent receiver and no
+// shielded or unknown receiver.
+func isTransparentOnly(addr *UnifiedAddress) bool {
+	return (addr.P2pkh != nil || addr.P2sh != nil) &&
+		addr.Sapling == nil && addr.Orchard == nil && len(addr.Unknown) == 0
+}
+
+// transparentOnlyAllowed reports whether opts' policy permits a
+// transparent-only receiver set: Revision 1 permits this unconditionally,
+// and AllowTransparentOnly permits it regardless of revision.
+func transparentOnlyAllowed(revision Revision, allowTransparentOnly bool) bool {
+	return allowTransparentOnly || revision == R1
+}
+
+func getExpectedLength(itemType DataTypecode) uint64 {
 	switch itemType {
 	case P2PKHItem:
 		return 20
@@ -40,7 +105,18 @@ func getExpectedLength(itemType ItemType) uint64 {
 	}
 }
 
-func getItemName(itemType ItemType) string {
+func getExpectedMetadataLength(itemType MetadataTypecode) uint64 {
+	switch itemType {
+	case ExpiryHeightItem:
+		return 4
+	case ExpiryTimeItem:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func getItemName(itemType DataTypecode) string {
 	var itemName string
 
 	switch itemType {
@@ -56,28 +132,59 @@ func getItemName(itemType ItemType) string {
 	return itemName
 }
 
-func tlv(typecode uint64, value []byte) ([]byte, error) {
-	start, err := compactsize.WriteCompactSize(typecode, true)
-	if err != nil {
-		return nil, err
-	}
+// tlvEntry is a generic (typecode, data) pair used to sort data and metadata
+// items into ascending typecode order before TLV encoding.
+type tlvEntry struct {
+	typecode uint64
+	data     []byte
+}
 
-	simplified := uint64(len(value))
-	st, err2 := compactsize.WriteCompactSize(simplified, true)
-	if err2 != nil {
-		return nil, err
+// dataItems returns addr's data items (receivers) as TLV entries, sorted in
+// ascending typecode order as required by ZIP 316.
+func dataItems(addr *UnifiedAddress) []tlvEntry {
+	items := make([]tlvEntry, 0)
+	if addr.P2pkh != nil {
+		items = append(items, tlvEntry{uint64(P2PKHItem), addr.P2pkh[:]})
 	}
-
-	return append(start, append(st, value...)...), nil
+	if addr.P2sh != nil {
+		items = append(items, tlvEntry{uint64(P2SHItem), addr.P2sh[:]})
+	}
+	if addr.Sapling != nil {
+		items = append(items, tlvEntry{uint64(SaplingItem), addr.Sapling[:]})
+	}
+	if addr.Orchard != nil {
+		items = append(items, tlvEntry{uint64(OrchardItem), addr.Orchard[:]})
+	}
+	for typecode, data := range addr.Unknown {
+		if len(data) > 0 {
+			items = append(items, tlvEntry{typecode, data})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].typecode < items[j].typecode })
+	return items
 }
 
-func padding(hrp string) []byte {
-	hrpBytes := []byte(hrp)
-	padLength := 16 - len(hrpBytes)
-	if padLength < 0 {
-		padLength = 0
+// metadataItems returns addr's metadata items as TLV entries, sorted in ascending
+// typecode order as required by ZIP 316 Revision 1.
+func metadataItems(addr *UnifiedAddress) []tlvEntry {
+	items := make([]tlvEntry, 0)
+	if addr.ExpiryHeight != nil {
+		data := make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, *addr.ExpiryHeight)
+		items = append(items, tlvEntry{uint64(ExpiryHeightItem), data})
+	}
+	if addr.ExpiryTime != nil {
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, *addr.ExpiryTime)
+		items = append(items, tlvEntry{uint64(ExpiryTimeItem), data})
+	}
+	for typecode, data := range addr.UnknownMetadata {
+		if len(data) > 0 {
+			items = append(items, tlvEntry{typecode, data})
+		}
 	}
-	return append(hrpBytes, bytes.Repeat([]byte{0x00}, padLength)...)
+	sort.Slice(items, func(i, j int) bool { return items[i].typecode < items[j].typecode })
+	return items
 }
 
 // A Zcash Unified Address.
@@ -89,128 +196,279 @@ type UnifiedAddress struct {
 	Sapling *[43]byte
 	Orchard *[43]byte
 	Unknown map[uint64][]byte
+
+	// Revision is the ZIP 316 revision this address was decoded from. The
+	// encoders do not read it: the revision to encode as is chosen by the
+	// encoding function or by EncodeUnifiedOpts. R0 addresses may not carry any
+	// metadata items.
+	Revision Revision
+
+	// ExpiryHeight is the MUST-understand expiry-height metadata item (typecode
+	// 0xE0), if present. Only valid for Revision R1 addresses.
+	ExpiryHeight *uint32
+	// ExpiryTime is the MUST-understand expiry-time metadata item (typecode
+	// 0xE1), if present. Only valid for Revision R1 addresses.
+	ExpiryTime *uint64
+	// UnknownMetadata holds MAY-understand metadata items that this package does
+	// not know how to interpret, keyed by typecode.
+	UnknownMetadata map[uint64][]byte
+}
+
+// MetadataItems returns addr's metadata items, in ascending typecode order, as a
+// single slice covering both the metadata items this package understands
+// (ExpiryHeight, ExpiryTime) and those preserved in UnknownMetadata.
+func (addr *UnifiedAddress) MetadataItems() []MetadataItem {
+	entries := metadataItems(addr)
+	items := make([]MetadataItem, len(entries))
+	for i, entry := range entries {
+		items[i] = MetadataItem{Typecode: MetadataTypecode(entry.typecode), Data: entry.data}
+	}
+	return items
+}
+
+// Receiver is a single typed receiver extracted from a UnifiedAddress,
+// independent of which named field (P2pkh, P2sh, Sapling, Orchard, Unknown)
+// it came from.
+type Receiver struct {
+	Typecode uint64
+	Data     []byte
+}
+
+// receiverPreference ranks a receiver typecode by preference: lower values
+// sort first. Shielded receivers are preferred over transparent ones, and
+// Orchard is preferred over Sapling; unrecognized typecodes are least
+// preferred and break ties in ascending typecode order.
+func receiverPreference(typecode uint64) int {
+	switch DataTypecode(typecode) {
+	case OrchardItem:
+		return 0
+	case SaplingItem:
+		return 1
+	case P2SHItem:
+		return 2
+	case P2PKHItem:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Receivers returns addr's receivers in preference order, most-private
+// first: Orchard, Sapling, P2sh, P2pkh, then any unrecognized receiver
+// typecodes in ascending order.
+func (addr *UnifiedAddress) Receivers() []Receiver {
+	entries := dataItems(addr)
+	sort.SliceStable(entries, func(i, j int) bool {
+		pi, pj := receiverPreference(entries[i].typecode), receiverPreference(entries[j].typecode)
+		if pi != pj {
+			return pi < pj
+		}
+		return entries[i].typecode < entries[j].typecode
+	})
+	receivers := make([]Receiver, len(entries))
+	for i, entry := range entries {
+		receivers[i] = Receiver{Typecode: entry.typecode, Data: entry.data}
+	}
+	return receivers
+}
+
+// HasReceiverOfType reports whether addr contains a receiver with the given
+// typecode.
+func (addr *UnifiedAddress) HasReceiverOfType(tc uint64) bool {
+	for _, r := range addr.Receivers() {
+		if r.Typecode == tc {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsReceiver reports whether addr contains a receiver exactly matching
+// r, comparing both typecode and data.
+func (addr *UnifiedAddress) ContainsReceiver(r Receiver) bool {
+	for _, existing := range addr.Receivers() {
+		if existing.Typecode == r.Typecode && bytes.Equal(existing.Data, r.Data) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanReceiveMemo reports whether addr has a shielded receiver (Sapling or
+// Orchard) capable of carrying a memo.
+func (addr *UnifiedAddress) CanReceiveMemo() bool {
+	return addr.Sapling != nil || addr.Orchard != nil
+}
+
+// PreferredReceiver returns addr's most-preferred receiver whose typecode
+// appears in supported, or nil if addr has no receiver of a supported type.
+func (addr *UnifiedAddress) PreferredReceiver(supported []uint64) *Receiver {
+	supportedSet := make(map[uint64]bool, len(supported))
+	for _, tc := range supported {
+		supportedSet[tc] = true
+	}
+	for _, r := range addr.Receivers() {
+		if supportedSet[r.Typecode] {
+			return &r
+		}
+	}
+	return nil
+}
+
+// EncodeUnifiedOpts controls the ZIP 316 policy used by EncodeUnifiedWithOpts.
+type EncodeUnifiedOpts struct {
+	// Revision selects which ZIP 316 revision to encode as. The zero value, R0,
+	// produces the original ZIP 316 encoding.
+	Revision Revision
+	// AllowTransparentOnly permits encoding a UnifiedAddress with only a P2pkh
+	// or P2sh receiver and no shielded receiver. Revision 1 permits this
+	// unconditionally; this flag exists to allow the same relaxation for
+	// Revision 0 callers that need it.
+	AllowTransparentOnly bool
 }
 
 // Encodes a UnifiedAddress to its string representation as defined in
-// [ZIP 316].
+// [ZIP 316] Revision 0.
 //
 // This function will return an error if the UnifiedAddress contains both
-// P2pkh and P2sh receivers.
+// P2pkh and P2sh receivers, carries any metadata items (Revision 0 has no
+// metadata item support), or contains only a transparent receiver
+// (ErrTransparentOnlyR0). Use [EncodeUnifiedWithOpts] to relax these
+// restrictions or to encode an address with metadata items.
 //
 // [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-addresses
 func EncodeUnified(addr *UnifiedAddress, hrp string) (string, error) {
+	return EncodeUnifiedWithOpts(addr, hrp, EncodeUnifiedOpts{Revision: R0})
+}
+
+// Encodes a UnifiedAddress to its string representation as defined in
+// [ZIP 316 Revision 1], permitting metadata items such as ExpiryHeight and
+// ExpiryTime to be attached, and permitting a transparent-only receiver set.
+// `hrp` must be the Revision 1 human-readable part for the target network
+// (e.g. "ur", "urtest", "urregtest").
+//
+// This function will return an error if the UnifiedAddress contains both
+// P2pkh and P2sh receivers.
+//
+// [ZIP 316 Revision 1]: https://zips.z.cash/zip-0316#encoding-of-unified-addresses
+func EncodeUnifiedR1(addr *UnifiedAddress, hrp string) (string, error) {
+	return EncodeUnifiedWithOpts(addr, hrp, EncodeUnifiedOpts{Revision: R1})
+}
+
+// Encodes a UnifiedAddress to its string representation as defined in
+// [ZIP 316], applying the policy in opts.
+//
+// [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-addresses
+func EncodeUnifiedWithOpts(addr *UnifiedAddress, hrp string, opts EncodeUnifiedOpts) (string, error) {
 	if addr.P2pkh != nil && addr.P2sh != nil {
 		return "", errors.New("both P2PKH and P2SH items found in unified address")
 	}
 
-	encodedItems := make([][]byte, 0)
-	if addr.P2pkh != nil {
-		tlvVal, err := tlv(uint64(P2PKHItem), addr.P2pkh[:])
-		if err != nil {
-			return "", err
-		} else {
-			encodedItems = append(encodedItems, tlvVal)
-		}
+	metadata := metadataItems(addr)
+	if opts.Revision == R0 && len(metadata) > 0 {
+		return "", errors.New("metadata items require unified address revision 1")
 	}
-	if addr.P2sh != nil {
-		tlvVal, err := tlv(uint64(P2SHItem), addr.P2sh[:])
-		if err != nil {
-			return "", err
-		} else {
-			encodedItems = append(encodedItems, tlvVal)
-		}
+
+	if !transparentOnlyAllowed(opts.Revision, opts.AllowTransparentOnly) && isTransparentOnly(addr) {
+		return "", ErrTransparentOnlyR0
 	}
-	if addr.Sapling != nil {
-		tlvVal, err := tlv(uint64(SaplingItem), addr.Sapling[:])
+
+	var rBytes []byte
+	for _, item := range dataItems(addr) {
+		tlvVal, err := unifiedencoding.TLV(item.typecode, item.data)
 		if err != nil {
 			return "", err
-		} else {
-			encodedItems = append(encodedItems, tlvVal)
 		}
+		rBytes = append(rBytes, tlvVal...)
 	}
-	if addr.Orchard != nil {
-		tlvVal, err := tlv(uint64(OrchardItem), addr.Orchard[:])
+	for _, item := range metadata {
+		tlvVal, err := unifiedencoding.TLV(item.typecode, item.data)
 		if err != nil {
 			return "", err
-		} else {
-			encodedItems = append(encodedItems, tlvVal)
 		}
+		rBytes = append(rBytes, tlvVal...)
 	}
-	for itemType, item := range addr.Unknown {
-		if len(item) > 0 {
-			tlvVal, err := tlv(uint64(itemType), item)
-			if err != nil {
-				return "", err
-			} else {
-				encodedItems = append(encodedItems, tlvVal)
-			}
-		}
-	}
-	encodedItems = append(encodedItems, padding(hrp))
-	var rBytes []byte
-	for _, item := range encodedItems {
-		rBytes = append(rBytes, item...)
-	}
-	jumbledBytes, err := f4jumble.F4Jumble(rBytes)
 
-	if err != nil {
-		return "", err
-	}
+	return unifiedencoding.Wrap(rBytes, hrp)
+}
 
-	converted, convertErr := bech32.ConvertBits(jumbledBytes, 8, 5, true)
-	if convertErr != nil {
-		return "", convertErr
-	}
-	encoded, encodeErr := bech32.EncodeM(hrp, converted)
-	if encodeErr != nil {
-		return "", encodeErr
-	}
-	return encoded, nil
+// DecodeUnifiedOpts controls the ZIP 316 policy used by DecodeUnifiedWithOpts.
+type DecodeUnifiedOpts struct {
+	// Revision selects which ZIP 316 revision to decode. The zero value, R0,
+	// decodes the original ZIP 316 encoding and rejects metadata items.
+	Revision Revision
+	// AllowTransparentOnly permits decoding a UnifiedAddress with only a
+	// P2pkh or P2sh receiver and no shielded receiver. Revision 1 permits
+	// this unconditionally; this flag exists to allow the same relaxation
+	// for Revision 0 callers that need it.
+	AllowTransparentOnly bool
 }
 
 // Decodes a UnifiedAddress from its string encoding as defined in
-// [ZIP 316].
+// [ZIP 316] Revision 0.
 //
 // This validates the encoded string against the provided expected human-readable
-// part, and returns an error if an unexpected HRP is encountered or if
-// the encoding is invalid.
+// part, and returns an error if an unexpected HRP is encountered, if
+// the encoding is invalid, or if the address contains only a transparent
+// receiver (ErrTransparentOnlyR0). Use [DecodeUnifiedWithOpts] to relax
+// this restriction.
 //
 // [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-addresses
 func DecodeUnified(encoded, expectedHrp string) (*UnifiedAddress, error) {
+	return DecodeUnifiedWithOpts(encoded, expectedHrp, DecodeUnifiedOpts{Revision: R0})
+}
 
-	hrp, data, version, encoding := bech32.DecodeNoLimitWithVersion(encoded)
-
-	if version != bech32.VersionM {
-		return nil, errors.New("unified addresses must be encoded with bech32m")
-	}
+// Decodes a UnifiedAddress from its string encoding as defined in
+// [ZIP 316 Revision 1], accepting metadata items appended after the data
+// items and a transparent-only receiver set. `expectedHrp` must be the
+// Revision 1 human-readable part for the target network (e.g. "ur",
+// "urtest", "urregtest").
+//
+// This rejects an encoding containing an unrecognized MUST-understand
+// metadata item (typecode 0xC0..0xFD), while preserving unrecognized
+// MAY-understand metadata items (typecode 0xFE and above) in
+// UnifiedAddress.UnknownMetadata.
+//
+// [ZIP 316 Revision 1]: https://zips.z.cash/zip-0316#encoding-of-unified-addresses
+func DecodeUnifiedR1(encoded, expectedHrp string) (*UnifiedAddress, error) {
+	return DecodeUnifiedWithOpts(encoded, expectedHrp, DecodeUnifiedOpts{Revision: R1})
+}
 
-	if hrp != expectedHrp || encoding != nil {
-		return nil, errors.New("invalid HRP or encoding")
-	}
-	if len(data) < 48 {
-		return nil, errors.New("invalid encoded data length")
+// Decodes a UnifiedAddress from its string encoding as defined in [ZIP 316],
+// applying the policy in opts.
+//
+// [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-addresses
+func DecodeUnifiedWithOpts(encoded, expectedHrp string, opts DecodeUnifiedOpts) (*UnifiedAddress, error) {
+	result, err := decodeUnified(encoded, expectedHrp, opts.Revision)
+	if err != nil {
+		return nil, err
 	}
-	convertedBits, convertedBitsErr := bech32.ConvertBits(data, 5, 8, false)
-	if convertedBitsErr != nil {
-		return nil, convertedBitsErr
-	}
-
-	decoded, decodedeErr := f4jumble.F4JumbleInv(convertedBits)
-	if decodedeErr != nil {
-		return nil, decodedeErr
+	if !transparentOnlyAllowed(opts.Revision, opts.AllowTransparentOnly) && isTransparentOnly(result) {
+		return nil, ErrTransparentOnlyR0
 	}
+	return result, nil
+}
 
-	suffix := decoded[len(decoded)-16:]
-	if !bytes.Equal(suffix, padding(expectedHrp)) {
-		return nil, errors.New("invalid trailing padding")
+func decodeUnified(encoded, expectedHrp string, revision Revision) (*UnifiedAddress, error) {
+	rest, err := unifiedencoding.Unwrap(encoded, expectedHrp, 48)
+	if err != nil {
+		return nil, err
 	}
-	rest := decoded[:len(decoded)-16]
 
-	receivers := make(map[uint64][]byte)
+	dataReceivers := make(map[uint64][]byte)
+	metadataReceivers := make(map[uint64][]byte)
 	// before we start define that we have not defined a "previous" item
 	prevType := NoPreviousItem
+	var prevMetadataType uint64
+	sawMetadata := false
 
 	for len(rest) > 0 {
+		// Wrap pads short item lists with trailing zero filler to meet
+		// F4Jumble's minimum length; stop here rather than misparsing it.
+		if unifiedencoding.IsZeroFiller(rest) {
+			break
+		}
+
 		itemType, remaining, e := compactsize.ParseCompactSize(rest, true)
 
 		if e != nil {
@@ -222,7 +480,20 @@ func DecodeUnified(encoded, expectedHrp string) (*UnifiedAddress, error) {
 			return nil, fmt.Errorf("error decoding item data %w", e2)
 		}
 
-		expectedLen := getExpectedLength(ItemType(itemType))
+		isMetadata := itemType >= metadataRangeStart
+		if isMetadata && revision == R0 {
+			return nil, fmt.Errorf("metadata item typecode %d is not permitted in a revision 0 unified address", itemType)
+		}
+		if !isMetadata && sawMetadata {
+			return nil, errors.New("data items must not follow metadata items")
+		}
+
+		var expectedLen uint64
+		if isMetadata {
+			expectedLen = getExpectedMetadataLength(MetadataTypecode(itemType))
+		} else {
+			expectedLen = getExpectedLength(DataTypecode(itemType))
+		}
 
 		if expectedLen > 0 && itemLen != expectedLen {
 			return nil, fmt.Errorf("incorrect item length for typecode %d", itemType)
@@ -235,22 +506,41 @@ func DecodeUnified(encoded, expectedHrp string) (*UnifiedAddress, error) {
 		item := remaining[:itemLen]
 		rest = remaining[itemLen:]
 
+		if isMetadata {
+			if _, exists := metadataReceivers[itemType]; exists {
+				return nil, fmt.Errorf("duplicate metadata item detected for typecode %d", itemType)
+			}
+			if itemType <= metadataMustUnderstandEnd &&
+				itemType != uint64(ExpiryHeightItem) && itemType != uint64(ExpiryTimeItem) {
+				return nil, fmt.Errorf("unrecognized MUST-understand metadata typecode %d", itemType)
+			}
+
+			metadataReceivers[itemType] = item
+			if sawMetadata && itemType <= prevMetadataType {
+				return nil, errors.New("metadata items out of order")
+			}
+			sawMetadata = true
+			prevMetadataType = itemType
+			continue
+		}
+
 		//check for duplicate names
-		if _, exists := receivers[itemType]; exists {
-			return nil, fmt.Errorf("duplicate %s item detected", getItemName(ItemType(itemType)))
+		if _, exists := dataReceivers[itemType]; exists {
+			return nil, fmt.Errorf("duplicate %s item detected", getItemName(DataTypecode(itemType)))
 		}
 
-		receivers[itemType] = item
+		dataReceivers[itemType] = item
 		// check order of returns
-		if prevType != NoPreviousItem && ItemType(itemType) <= prevType {
+		if prevType != NoPreviousItem && DataTypecode(itemType) <= prevType {
 			return nil, errors.New("items out of order")
 		}
-		prevType = ItemType(itemType)
+		prevType = DataTypecode(itemType)
 	}
 
 	result := new(UnifiedAddress)
+	result.Revision = revision
 	result.Unknown = make(map[uint64][]byte)
-	for itemType, data := range receivers {
+	for itemType, data := range dataReceivers {
 		switch itemType {
 		case uint64(P2PKHItem):
 			result.P2pkh = new([20]byte)
@@ -269,5 +559,19 @@ func DecodeUnified(encoded, expectedHrp string) (*UnifiedAddress, error) {
 		}
 	}
 
+	result.UnknownMetadata = make(map[uint64][]byte)
+	for itemType, data := range metadataReceivers {
+		switch MetadataTypecode(itemType) {
+		case ExpiryHeightItem:
+			v := binary.LittleEndian.Uint32(data)
+			result.ExpiryHeight = &v
+		case ExpiryTimeItem:
+			v := binary.LittleEndian.Uint64(data)
+			result.ExpiryTime = &v
+		default:
+			result.UnknownMetadata[itemType] = data
+		}
+	}
+
 	return result, nil
 }
@@ -0,0 +1,148 @@
+package unifiedaddress
+
+import (
+	"bytes"
+	"testing"
+)
+
+// FuzzUnifiedAddressRoundtrip asserts that decoding the encoding of an
+// arbitrary combination of receivers and metadata items (including unknown
+// typecodes with random payloads) produces a structurally equal
+// UnifiedAddress.
+func FuzzUnifiedAddressRoundtrip(f *testing.F) {
+	// Regression seeds covering P2pkh, Orchard and an unknown receiver type
+	// together, a P2pkh and Sapling pair, an Orchard-only address, an empty
+	// address (skipped), and (seed5) a P2sh-only address alongside expiry and
+	// unknown metadata items. Each address is built from its named fields and
+	// at most one Unknown entry, so no seed produces out-of-order or duplicate
+	// typecodes.
+	f.Add(true, false, true, true, uint64(0x04), []byte("unknown-receiver-payload"), false, false, false, false, uint64(0), []byte{})
+	f.Add(true, true, false, false, uint64(0x00), []byte(""), false, false, false, false, uint64(0), []byte{})
+	f.Add(false, false, true, false, uint64(0xBF), []byte{0x00, 0x01, 0x02, 0x03}, false, false, false, false, uint64(0), []byte{})
+	f.Add(false, false, false, false, uint64(0x05), []byte{}, false, false, false, false, uint64(0), []byte{})
+	f.Add(false, false, false, false, uint64(0), []byte{}, true, true, true, true, uint64(0x20), []byte("unknown-metadata-payload"))
+
+	f.Fuzz(func(t *testing.T, hasP2pkh, hasSapling, hasOrchard, hasUnknown bool, unknownTypecode uint64, unknownData []byte,
+		hasP2sh, hasExpiryHeight, hasExpiryTime, hasUnknownMetadata bool, unknownMetadataTypecode uint64, unknownMetadataData []byte) {
+		addr := &UnifiedAddress{}
+		if hasP2pkh {
+			addr.P2pkh = new([20]byte)
+			copy(addr.P2pkh[:], bytes.Repeat([]byte{0xAB}, 20))
+		}
+		if hasP2sh {
+			addr.P2sh = new([20]byte)
+			copy(addr.P2sh[:], bytes.Repeat([]byte{0x12}, 20))
+		}
+		if hasSapling {
+			addr.Sapling = new([43]byte)
+			copy(addr.Sapling[:], bytes.Repeat([]byte{0xCD}, 43))
+		}
+		if hasOrchard {
+			addr.Orchard = new([43]byte)
+			copy(addr.Orchard[:], bytes.Repeat([]byte{0xEF}, 43))
+		}
+		// Unknown typecodes must avoid the metadata range and the known data
+		// typecodes to exercise the Unknown map without colliding with a
+		// named receiver.
+		typecode := (unknownTypecode % (metadataRangeStart - 4)) + 4
+		if hasUnknown && len(unknownData) > 0 {
+			addr.Unknown = map[uint64][]byte{typecode: unknownData}
+		}
+		if hasExpiryHeight {
+			v := uint32(0x01020304)
+			addr.ExpiryHeight = &v
+		}
+		if hasExpiryTime {
+			v := uint64(0x0102030405060708)
+			addr.ExpiryTime = &v
+		}
+		// Unknown metadata typecodes must land in the MAY-understand range
+		// (above metadataMustUnderstandEnd) so they do not collide with
+		// ExpiryHeightItem/ExpiryTimeItem or trip the MUST-understand rejection.
+		metadataTypecode := metadataMustUnderstandEnd + 1 + (unknownMetadataTypecode % 1000)
+		if hasUnknownMetadata && len(unknownMetadataData) > 0 {
+			addr.UnknownMetadata = map[uint64][]byte{metadataTypecode: unknownMetadataData}
+		}
+
+		if addr.P2pkh == nil && addr.P2sh == nil && addr.Sapling == nil && addr.Orchard == nil &&
+			len(addr.Unknown) == 0 && addr.ExpiryHeight == nil && addr.ExpiryTime == nil && len(addr.UnknownMetadata) == 0 {
+			return
+		}
+
+		encoded, err := EncodeUnifiedR1(addr, "u")
+		if err != nil {
+			if addr.P2pkh != nil && addr.P2sh != nil {
+				t.Skip()
+			}
+			t.Fatalf("failed to encode: %v", err)
+		}
+
+		decoded, err := DecodeUnifiedWithOpts(encoded, "u", DecodeUnifiedOpts{Revision: R1, AllowTransparentOnly: true})
+		if err != nil {
+			t.Fatalf("failed to decode its own encoding: %v", err)
+		}
+
+		if !equalBytesPtr20(addr.P2pkh, decoded.P2pkh) {
+			t.Fatalf("P2pkh mismatch: %v != %v", addr.P2pkh, decoded.P2pkh)
+		}
+		if !equalBytesPtr20(addr.P2sh, decoded.P2sh) {
+			t.Fatalf("P2sh mismatch: %v != %v", addr.P2sh, decoded.P2sh)
+		}
+		if !equalBytesPtr43(addr.Sapling, decoded.Sapling) {
+			t.Fatalf("Sapling mismatch: %v != %v", addr.Sapling, decoded.Sapling)
+		}
+		if !equalBytesPtr43(addr.Orchard, decoded.Orchard) {
+			t.Fatalf("Orchard mismatch: %v != %v", addr.Orchard, decoded.Orchard)
+		}
+		if len(addr.Unknown) != len(decoded.Unknown) {
+			t.Fatalf("Unknown item count mismatch: %d != %d", len(addr.Unknown), len(decoded.Unknown))
+		}
+		for tc, data := range addr.Unknown {
+			if !bytes.Equal(decoded.Unknown[tc], data) {
+				t.Fatalf("Unknown item %d mismatch: %x != %x", tc, decoded.Unknown[tc], data)
+			}
+		}
+		if !equalUint32Ptr(addr.ExpiryHeight, decoded.ExpiryHeight) {
+			t.Fatalf("ExpiryHeight mismatch: %v != %v", addr.ExpiryHeight, decoded.ExpiryHeight)
+		}
+		if !equalUint64Ptr(addr.ExpiryTime, decoded.ExpiryTime) {
+			t.Fatalf("ExpiryTime mismatch: %v != %v", addr.ExpiryTime, decoded.ExpiryTime)
+		}
+		if len(addr.UnknownMetadata) != len(decoded.UnknownMetadata) {
+			t.Fatalf("UnknownMetadata item count mismatch: %d != %d", len(addr.UnknownMetadata), len(decoded.UnknownMetadata))
+		}
+		for tc, data := range addr.UnknownMetadata {
+			if !bytes.Equal(decoded.UnknownMetadata[tc], data) {
+				t.Fatalf("UnknownMetadata item %d mismatch: %x != %x", tc, decoded.UnknownMetadata[tc], data)
+			}
+		}
+	})
+}
+
+func equalUint32Ptr(a, b *uint32) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || *a == *b
+}
+
+func equalUint64Ptr(a, b *uint64) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || *a == *b
+}
+
+func equalBytesPtr20(a, b *[20]byte) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || bytes.Equal(a[:], b[:])
+}
+
+func equalBytesPtr43(a, b *[43]byte) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || bytes.Equal(a[:], b[:])
+}
@@ -0,0 +1,178 @@
+package unifiedaddress
+
+import (
+	"testing"
+
+	"github.com/MarcoEzekiel/go-zcashaddress/internal/unifiedencoding"
+)
+
+// encodeRawR1 TLV-encodes items in the given order without sorting or
+// validating them, so tests can construct malformed item lists that
+// EncodeUnifiedWithOpts would otherwise refuse to produce.
+func encodeRawR1(items []tlvEntry, hrp string) (string, error) {
+	var rBytes []byte
+	for _, item := range items {
+		tlvVal, err := unifiedencoding.TLV(item.typecode, item.data)
+		if err != nil {
+			return "", err
+		}
+		rBytes = append(rBytes, tlvVal...)
+	}
+	return unifiedencoding.Wrap(rBytes, hrp)
+}
+
+func ptr20(fill byte) *[20]byte {
+	var out [20]byte
+	for i := range out {
+		out[i] = fill
+	}
+	return &out
+}
+
+func ptr43(fill byte) *[43]byte {
+	var out [43]byte
+	for i := range out {
+		out[i] = fill
+	}
+	return &out
+}
+
+func TestReceiversPreferenceOrder(t *testing.T) {
+	addr := &UnifiedAddress{
+		P2pkh:   ptr20(0xAB),
+		Sapling: ptr43(0xCD),
+		Orchard: ptr43(0xEF),
+		Unknown: map[uint64][]byte{0x09: {0x01}},
+	}
+
+	receivers := addr.Receivers()
+
+	wantOrder := []uint64{uint64(OrchardItem), uint64(SaplingItem), uint64(P2PKHItem), 0x09}
+	if len(receivers) != len(wantOrder) {
+		t.Fatalf("got %d receivers, want %d", len(receivers), len(wantOrder))
+	}
+	for i, want := range wantOrder {
+		if receivers[i].Typecode != want {
+			t.Errorf("receiver %d: got typecode %d, want %d", i, receivers[i].Typecode, want)
+		}
+	}
+}
+
+func TestReceiversPrefersP2shOverP2pkh(t *testing.T) {
+	addr := &UnifiedAddress{P2pkh: ptr20(0xCD), P2sh: ptr20(0xAB)}
+	receivers := addr.Receivers()
+	if len(receivers) != 2 || receivers[0].Typecode != uint64(P2SHItem) || receivers[1].Typecode != uint64(P2PKHItem) {
+		t.Fatalf("got %v, want the P2SHItem receiver ahead of the P2PKHItem receiver", receivers)
+	}
+}
+
+func TestHasReceiverOfType(t *testing.T) {
+	addr := &UnifiedAddress{P2pkh: ptr20(0xAB), Orchard: ptr43(0xEF)}
+
+	if !addr.HasReceiverOfType(uint64(P2PKHItem)) {
+		t.Error("expected HasReceiverOfType(P2PKHItem) to be true")
+	}
+	if !addr.HasReceiverOfType(uint64(OrchardItem)) {
+		t.Error("expected HasReceiverOfType(OrchardItem) to be true")
+	}
+	if addr.HasReceiverOfType(uint64(SaplingItem)) {
+		t.Error("expected HasReceiverOfType(SaplingItem) to be false")
+	}
+}
+
+func TestContainsReceiver(t *testing.T) {
+	addr := &UnifiedAddress{P2pkh: ptr20(0xAB)}
+
+	if !addr.ContainsReceiver(Receiver{Typecode: uint64(P2PKHItem), Data: ptr20(0xAB)[:]}) {
+		t.Error("expected ContainsReceiver to match the P2pkh receiver")
+	}
+	if addr.ContainsReceiver(Receiver{Typecode: uint64(P2PKHItem), Data: ptr20(0xCD)[:]}) {
+		t.Error("expected ContainsReceiver to reject a receiver with mismatched data")
+	}
+	if addr.ContainsReceiver(Receiver{Typecode: uint64(SaplingItem), Data: ptr43(0xCD)[:]}) {
+		t.Error("expected ContainsReceiver to reject a receiver the address does not have")
+	}
+}
+
+func TestCanReceiveMemo(t *testing.T) {
+	tests := []struct {
+		name string
+		addr *UnifiedAddress
+		want bool
+	}{
+		{"p2pkh only", &UnifiedAddress{P2pkh: ptr20(0xAB)}, false},
+		{"sapling", &UnifiedAddress{Sapling: ptr43(0xCD)}, true},
+		{"orchard", &UnifiedAddress{Orchard: ptr43(0xEF)}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.addr.CanReceiveMemo(); got != test.want {
+				t.Errorf("CanReceiveMemo() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPreferredReceiver(t *testing.T) {
+	addr := &UnifiedAddress{
+		P2pkh:   ptr20(0xAB),
+		Sapling: ptr43(0xCD),
+	}
+
+	receiver := addr.PreferredReceiver([]uint64{uint64(P2PKHItem), uint64(SaplingItem)})
+	if receiver == nil || receiver.Typecode != uint64(SaplingItem) {
+		t.Fatalf("got %v, want the Sapling receiver", receiver)
+	}
+
+	receiver = addr.PreferredReceiver([]uint64{uint64(P2PKHItem)})
+	if receiver == nil || receiver.Typecode != uint64(P2PKHItem) {
+		t.Fatalf("got %v, want the P2pkh receiver", receiver)
+	}
+
+	if receiver := addr.PreferredReceiver([]uint64{uint64(OrchardItem)}); receiver != nil {
+		t.Fatalf("got %v, want nil for an unsupported receiver set", receiver)
+	}
+}
+
+func TestDecodeRejectsUnrecognizedMustUnderstandMetadata(t *testing.T) {
+	// 0xC5 falls in the MUST-understand metadata range but is not
+	// ExpiryHeightItem or ExpiryTimeItem, so a Revision 1 decoder that does
+	// not recognize it must reject the address.
+	encoded, err := encodeRawR1([]tlvEntry{
+		{uint64(SaplingItem), ptr43(0xCD)[:]},
+		{0xC5, []byte{0x01, 0x02, 0x03}},
+	}, "u")
+	if err != nil {
+		t.Fatalf("encodeRawR1 failed: %v", err)
+	}
+	if _, err := DecodeUnifiedR1(encoded, "u"); err == nil {
+		t.Fatal("expected DecodeUnifiedR1 to reject an unrecognized MUST-understand metadata typecode")
+	}
+}
+
+func TestDecodeRejectsDataItemAfterMetadataItem(t *testing.T) {
+	encoded, err := encodeRawR1([]tlvEntry{
+		{uint64(ExpiryHeightItem), []byte{0x01, 0x02, 0x03, 0x04}},
+		{uint64(OrchardItem), ptr43(0xEF)[:]},
+	}, "u")
+	if err != nil {
+		t.Fatalf("encodeRawR1 failed: %v", err)
+	}
+	if _, err := DecodeUnifiedR1(encoded, "u"); err == nil {
+		t.Fatal("expected DecodeUnifiedR1 to reject a data item following a metadata item")
+	}
+}
+
+func TestDecodeRejectsOutOfOrderMetadataItems(t *testing.T) {
+	encoded, err := encodeRawR1([]tlvEntry{
+		{uint64(SaplingItem), ptr43(0xCD)[:]},
+		{uint64(ExpiryTimeItem), []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{uint64(ExpiryHeightItem), []byte{0x01, 0x02, 0x03, 0x04}},
+	}, "u")
+	if err != nil {
+		t.Fatalf("encodeRawR1 failed: %v", err)
+	}
+	if _, err := DecodeUnifiedR1(encoded, "u"); err == nil {
+		t.Fatal("expected DecodeUnifiedR1 to reject out-of-order metadata items")
+	}
+}
@@ -0,0 +1,253 @@
+// package unifiedviewingkey
+//
+// Encodes and decodes Zcash Unified Full Viewing Keys (UFVKs) and Unified
+// Incoming Viewing Keys (UIVKs) from their serialized string representations,
+// as defined in [ZIP 316].
+//
+// [ZIP 316]: https://zips.z.cash/zip-0316
+package unifiedviewingkey
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/MarcoEzekiel/go-zcashaddress/internal/compactsize"
+	"github.com/MarcoEzekiel/go-zcashaddress/internal/unifiedencoding"
+)
+
+// Typecode identifies a viewing key component item within a UFVK or UIVK.
+type Typecode uint64
+
+// noPreviousItem is a max value for ordering checks in decode().
+const noPreviousItem Typecode = 0xffffffff
+
+const (
+	P2PKHItem   Typecode = 0x00
+	SaplingItem Typecode = 0x02
+	OrchardItem Typecode = 0x03
+)
+
+func getItemName(itemType Typecode) string {
+	switch itemType {
+	case P2PKHItem:
+		return "transparent"
+	case SaplingItem:
+		return "sapling"
+	case OrchardItem:
+		return "orchard"
+	default:
+		return "unknown"
+	}
+}
+
+// A Zcash Unified Full Viewing Key.
+type UnifiedFVK struct {
+	P2pkh   *[65]byte
+	Sapling *[128]byte
+	Orchard *[96]byte
+	Unknown map[uint64][]byte
+}
+
+// A Zcash Unified Incoming Viewing Key.
+type UnifiedIVK struct {
+	P2pkh   *[65]byte
+	Sapling *[64]byte
+	Orchard *[64]byte
+	Unknown map[uint64][]byte
+}
+
+type tlvEntry struct {
+	typecode uint64
+	data     []byte
+}
+
+// EncodeFVK encodes a UnifiedFVK to its string representation as defined in
+// [ZIP 316].
+//
+// [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-full-viewing-keys
+func EncodeFVK(fvk *UnifiedFVK, hrp string) (string, error) {
+	items := make([]tlvEntry, 0)
+	if fvk.P2pkh != nil {
+		items = append(items, tlvEntry{uint64(P2PKHItem), fvk.P2pkh[:]})
+	}
+	if fvk.Sapling != nil {
+		items = append(items, tlvEntry{uint64(SaplingItem), fvk.Sapling[:]})
+	}
+	if fvk.Orchard != nil {
+		items = append(items, tlvEntry{uint64(OrchardItem), fvk.Orchard[:]})
+	}
+	for typecode, data := range fvk.Unknown {
+		if len(data) > 0 {
+			items = append(items, tlvEntry{typecode, data})
+		}
+	}
+	return encode(items, hrp)
+}
+
+// DecodeFVK decodes a UnifiedFVK from its string encoding as defined in
+// [ZIP 316].
+//
+// [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-full-viewing-keys
+func DecodeFVK(encoded, expectedHrp string) (*UnifiedFVK, error) {
+	items, err := decode(encoded, expectedHrp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(UnifiedFVK)
+	result.Unknown = make(map[uint64][]byte)
+	for itemType, data := range items {
+		switch itemType {
+		case uint64(P2PKHItem):
+			if len(data) != 65 {
+				return nil, fmt.Errorf("incorrect item length for typecode %d", itemType)
+			}
+			result.P2pkh = new([65]byte)
+			copy(result.P2pkh[:], data)
+		case uint64(SaplingItem):
+			if len(data) != 128 {
+				return nil, fmt.Errorf("incorrect item length for typecode %d", itemType)
+			}
+			result.Sapling = new([128]byte)
+			copy(result.Sapling[:], data)
+		case uint64(OrchardItem):
+			if len(data) != 96 {
+				return nil, fmt.Errorf("incorrect item length for typecode %d", itemType)
+			}
+			result.Orchard = new([96]byte)
+			copy(result.Orchard[:], data)
+		default:
+			result.Unknown[itemType] = data
+		}
+	}
+	return result, nil
+}
+
+// EncodeIVK encodes a UnifiedIVK to its string representation as defined in
+// [ZIP 316].
+//
+// [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-incoming-viewing-keys
+func EncodeIVK(ivk *UnifiedIVK, hrp string) (string, error) {
+	items := make([]tlvEntry, 0)
+	if ivk.P2pkh != nil {
+		items = append(items, tlvEntry{uint64(P2PKHItem), ivk.P2pkh[:]})
+	}
+	if ivk.Sapling != nil {
+		items = append(items, tlvEntry{uint64(SaplingItem), ivk.Sapling[:]})
+	}
+	if ivk.Orchard != nil {
+		items = append(items, tlvEntry{uint64(OrchardItem), ivk.Orchard[:]})
+	}
+	for typecode, data := range ivk.Unknown {
+		if len(data) > 0 {
+			items = append(items, tlvEntry{typecode, data})
+		}
+	}
+	return encode(items, hrp)
+}
+
+// DecodeIVK decodes a UnifiedIVK from its string encoding as defined in
+// [ZIP 316].
+//
+// [ZIP 316]: https://zips.z.cash/zip-0316#encoding-of-unified-incoming-viewing-keys
+func DecodeIVK(encoded, expectedHrp string) (*UnifiedIVK, error) {
+	items, err := decode(encoded, expectedHrp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(UnifiedIVK)
+	result.Unknown = make(map[uint64][]byte)
+	for itemType, data := range items {
+		switch itemType {
+		case uint64(P2PKHItem):
+			if len(data) != 65 {
+				return nil, fmt.Errorf("incorrect item length for typecode %d", itemType)
+			}
+			result.P2pkh = new([65]byte)
+			copy(result.P2pkh[:], data)
+		case uint64(SaplingItem):
+			if len(data) != 64 {
+				return nil, fmt.Errorf("incorrect item length for typecode %d", itemType)
+			}
+			result.Sapling = new([64]byte)
+			copy(result.Sapling[:], data)
+		case uint64(OrchardItem):
+			if len(data) != 64 {
+				return nil, fmt.Errorf("incorrect item length for typecode %d", itemType)
+			}
+			result.Orchard = new([64]byte)
+			copy(result.Orchard[:], data)
+		default:
+			result.Unknown[itemType] = data
+		}
+	}
+	return result, nil
+}
+
+// encode sorts items into ascending typecode order, TLV-encodes each, and
+// wraps the concatenated result as defined in ZIP 316.
+func encode(items []tlvEntry, hrp string) (string, error) {
+	sort.Slice(items, func(i, j int) bool { return items[i].typecode < items[j].typecode })
+
+	var rBytes []byte
+	for _, item := range items {
+		tlvVal, err := unifiedencoding.TLV(item.typecode, item.data)
+		if err != nil {
+			return "", err
+		}
+		rBytes = append(rBytes, tlvVal...)
+	}
+	return unifiedencoding.Wrap(rBytes, hrp)
+}
+
+// decode unwraps encoded against expectedHrp and parses its TLV item list,
+// validating that items are in strictly ascending typecode order with no
+// duplicates.
+func decode(encoded, expectedHrp string) (map[uint64][]byte, error) {
+	rest, err := unifiedencoding.Unwrap(encoded, expectedHrp, 48)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[uint64][]byte)
+	prevType := noPreviousItem
+
+	for len(rest) > 0 {
+		// Wrap pads short item lists with trailing zero filler to meet
+		// F4Jumble's minimum length; stop here rather than misparsing it.
+		if unifiedencoding.IsZeroFiller(rest) {
+			break
+		}
+
+		itemType, remaining, e := compactsize.ParseCompactSize(rest, true)
+		if e != nil {
+			return nil, fmt.Errorf("error decoding item type %w", e)
+		}
+
+		itemLen, remaining, e2 := compactsize.ParseCompactSize(remaining, true)
+		if e2 != nil {
+			return nil, fmt.Errorf("error decoding item data %w", e2)
+		}
+
+		if len(remaining) < int(itemLen) {
+			return nil, fmt.Errorf("insufficient data for item with typecode %d", itemType)
+		}
+
+		item := remaining[:itemLen]
+		rest = remaining[itemLen:]
+
+		if _, exists := items[itemType]; exists {
+			return nil, fmt.Errorf("duplicate %s item detected", getItemName(Typecode(itemType)))
+		}
+
+		items[itemType] = item
+		if prevType != noPreviousItem && Typecode(itemType) <= prevType {
+			return nil, errors.New("items out of order")
+		}
+		prevType = Typecode(itemType)
+	}
+
+	return items, nil
+}
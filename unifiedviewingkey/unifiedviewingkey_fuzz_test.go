@@ -0,0 +1,48 @@
+package unifiedviewingkey
+
+import "testing"
+
+// FuzzFVKRoundtrip asserts that decoding the encoding of an arbitrary
+// combination of UnifiedFVK components produces a structurally equal value.
+func FuzzFVKRoundtrip(f *testing.F) {
+	f.Add(true, true, true)
+	f.Add(true, false, false)
+	f.Add(false, true, false)
+	f.Add(false, false, true)
+
+	f.Fuzz(func(t *testing.T, hasP2pkh, hasSapling, hasOrchard bool) {
+		fvk := &UnifiedFVK{}
+		if hasP2pkh {
+			fvk.P2pkh = ptr65(bytesOf(65, 0xAB))
+		}
+		if hasSapling {
+			fvk.Sapling = ptr128(bytesOf(128, 0xCD))
+		}
+		if hasOrchard {
+			fvk.Orchard = ptr96(bytesOf(96, 0xEF))
+		}
+		if fvk.P2pkh == nil && fvk.Sapling == nil && fvk.Orchard == nil {
+			return
+		}
+
+		encoded, err := EncodeFVK(fvk, "uview")
+		if err != nil {
+			t.Fatalf("EncodeFVK failed: %v", err)
+		}
+
+		decoded, err := DecodeFVK(encoded, "uview")
+		if err != nil {
+			t.Fatalf("failed to decode its own encoding: %v", err)
+		}
+
+		if !equalPtr65(fvk.P2pkh, decoded.P2pkh) {
+			t.Fatalf("P2pkh mismatch: %v != %v", fvk.P2pkh, decoded.P2pkh)
+		}
+		if !equalPtr128(fvk.Sapling, decoded.Sapling) {
+			t.Fatalf("Sapling mismatch: %v != %v", fvk.Sapling, decoded.Sapling)
+		}
+		if !equalPtr96(fvk.Orchard, decoded.Orchard) {
+			t.Fatalf("Orchard mismatch: %v != %v", fvk.Orchard, decoded.Orchard)
+		}
+	})
+}
@@ -0,0 +1,245 @@
+package unifiedviewingkey
+
+import (
+	"testing"
+
+	"github.com/MarcoEzekiel/go-zcashaddress/internal/unifiedencoding"
+)
+
+func bytesOf(n int, fill byte) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = fill
+	}
+	return data
+}
+
+func TestFVKRoundtrip(t *testing.T) {
+	tests := []struct {
+		name string
+		fvk  *UnifiedFVK
+	}{
+		{
+			name: "p2pkh only",
+			fvk:  &UnifiedFVK{P2pkh: ptr65(bytesOf(65, 0xAB))},
+		},
+		{
+			name: "sapling only",
+			fvk:  &UnifiedFVK{Sapling: ptr128(bytesOf(128, 0xCD))},
+		},
+		{
+			name: "orchard only",
+			fvk:  &UnifiedFVK{Orchard: ptr96(bytesOf(96, 0xEF))},
+		},
+		{
+			name: "p2pkh, sapling and orchard",
+			fvk: &UnifiedFVK{
+				P2pkh:   ptr65(bytesOf(65, 0xAB)),
+				Sapling: ptr128(bytesOf(128, 0xCD)),
+				Orchard: ptr96(bytesOf(96, 0xEF)),
+			},
+		},
+		{
+			name: "unknown item",
+			fvk: &UnifiedFVK{
+				Sapling: ptr128(bytesOf(128, 0xCD)),
+				Unknown: map[uint64][]byte{0x09: {0x01, 0x02, 0x03}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := EncodeFVK(test.fvk, "uview")
+			if err != nil {
+				t.Fatalf("EncodeFVK failed: %v", err)
+			}
+
+			decoded, err := DecodeFVK(encoded, "uview")
+			if err != nil {
+				t.Fatalf("DecodeFVK failed: %v", err)
+			}
+
+			if !equalPtr65(test.fvk.P2pkh, decoded.P2pkh) {
+				t.Errorf("P2pkh mismatch: %v != %v", test.fvk.P2pkh, decoded.P2pkh)
+			}
+			if !equalPtr128(test.fvk.Sapling, decoded.Sapling) {
+				t.Errorf("Sapling mismatch: %v != %v", test.fvk.Sapling, decoded.Sapling)
+			}
+			if !equalPtr96(test.fvk.Orchard, decoded.Orchard) {
+				t.Errorf("Orchard mismatch: %v != %v", test.fvk.Orchard, decoded.Orchard)
+			}
+			for typecode, data := range test.fvk.Unknown {
+				if string(decoded.Unknown[typecode]) != string(data) {
+					t.Errorf("Unknown item %d mismatch: %x != %x", typecode, decoded.Unknown[typecode], data)
+				}
+			}
+		})
+	}
+}
+
+func TestIVKRoundtrip(t *testing.T) {
+	tests := []struct {
+		name string
+		ivk  *UnifiedIVK
+	}{
+		{
+			name: "p2pkh only",
+			ivk:  &UnifiedIVK{P2pkh: ptr65(bytesOf(65, 0xAB))},
+		},
+		{
+			name: "sapling and orchard",
+			ivk: &UnifiedIVK{
+				Sapling: ptr64(bytesOf(64, 0xCD)),
+				Orchard: ptr64(bytesOf(64, 0xEF)),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := EncodeIVK(test.ivk, "uivk")
+			if err != nil {
+				t.Fatalf("EncodeIVK failed: %v", err)
+			}
+
+			decoded, err := DecodeIVK(encoded, "uivk")
+			if err != nil {
+				t.Fatalf("DecodeIVK failed: %v", err)
+			}
+
+			if !equalPtr65(test.ivk.P2pkh, decoded.P2pkh) {
+				t.Errorf("P2pkh mismatch: %v != %v", test.ivk.P2pkh, decoded.P2pkh)
+			}
+			if !equalPtr64(test.ivk.Sapling, decoded.Sapling) {
+				t.Errorf("Sapling mismatch: %v != %v", test.ivk.Sapling, decoded.Sapling)
+			}
+			if !equalPtr64(test.ivk.Orchard, decoded.Orchard) {
+				t.Errorf("Orchard mismatch: %v != %v", test.ivk.Orchard, decoded.Orchard)
+			}
+		})
+	}
+}
+
+func TestDecodeFVKRejectsWrongLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		fvk     *UnifiedFVK
+		corrupt func(data []byte) []byte
+	}{
+		{
+			name: "sapling of IVK length",
+			fvk:  &UnifiedFVK{P2pkh: ptr65(bytesOf(65, 0xAB))},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Encode a UIVK whose Sapling item is 64 bytes and feed it to
+			// DecodeFVK, which expects 128, to confirm the length check fires.
+			short := &UnifiedIVK{Sapling: ptr64(bytesOf(64, 0xCD))}
+			encoded, err := EncodeIVK(short, "uview")
+			if err != nil {
+				t.Fatalf("EncodeIVK failed: %v", err)
+			}
+			if _, err := DecodeFVK(encoded, "uview"); err == nil {
+				t.Fatal("expected DecodeFVK to reject a sapling item of IVK length")
+			}
+		})
+	}
+}
+
+func TestDecodeRejectsDuplicateTypecode(t *testing.T) {
+	// Hand-roll a TLV item list with the same typecode twice so that decode()'s
+	// duplicate check is exercised directly.
+	tlv, err := encodeRaw([]tlvEntry{
+		{uint64(P2PKHItem), bytesOf(65, 0xAB)},
+		{uint64(P2PKHItem), bytesOf(65, 0xCD)},
+	}, "uview")
+	if err != nil {
+		t.Fatalf("encodeRaw failed: %v", err)
+	}
+	if _, err := decode(tlv, "uview"); err == nil {
+		t.Fatal("expected decode to reject a duplicate typecode")
+	}
+}
+
+func TestDecodeRejectsOutOfOrderTypecode(t *testing.T) {
+	tlv, err := encodeRaw([]tlvEntry{
+		{uint64(OrchardItem), bytesOf(96, 0xAB)},
+		{uint64(SaplingItem), bytesOf(128, 0xCD)},
+	}, "uview")
+	if err != nil {
+		t.Fatalf("encodeRaw failed: %v", err)
+	}
+	if _, err := decode(tlv, "uview"); err == nil {
+		t.Fatal("expected decode to reject out-of-order typecodes")
+	}
+}
+
+// encodeRaw TLV-encodes items in the given order without sorting them first,
+// so tests can construct malformed item lists that encode() would otherwise
+// refuse to produce.
+func encodeRaw(items []tlvEntry, hrp string) (string, error) {
+	var rBytes []byte
+	for _, item := range items {
+		tlvVal, err := unifiedencoding.TLV(item.typecode, item.data)
+		if err != nil {
+			return "", err
+		}
+		rBytes = append(rBytes, tlvVal...)
+	}
+	return unifiedencoding.Wrap(rBytes, hrp)
+}
+
+func ptr65(data []byte) *[65]byte {
+	var out [65]byte
+	copy(out[:], data)
+	return &out
+}
+
+func ptr128(data []byte) *[128]byte {
+	var out [128]byte
+	copy(out[:], data)
+	return &out
+}
+
+func ptr96(data []byte) *[96]byte {
+	var out [96]byte
+	copy(out[:], data)
+	return &out
+}
+
+func ptr64(data []byte) *[64]byte {
+	var out [64]byte
+	copy(out[:], data)
+	return &out
+}
+
+func equalPtr65(a, b *[65]byte) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || *a == *b
+}
+
+func equalPtr128(a, b *[128]byte) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || *a == *b
+}
+
+func equalPtr96(a, b *[96]byte) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || *a == *b
+}
+
+func equalPtr64(a, b *[64]byte) bool {
+	if (a == nil) != (b == nil) {
+		return false
+	}
+	return a == nil || *a == *b
+}
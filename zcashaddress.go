@@ -16,6 +16,12 @@ import (
 	"github.com/btcsuite/btcd/btcutil/bech32"
 )
 
+// ErrTransparentOnlyR0 is returned by DecodeAddress when a decoded Unified
+// Address contains only a transparent receiver, which ZIP 316 Revision 0
+// disallows by default, so callers can distinguish a policy rejection from a
+// malformed address.
+var ErrTransparentOnlyR0 = unifiedaddress.ErrTransparentOnlyR0
+
 // A parsed Zcash address. Fields of this structure are mutually exclusive; only one field may be non-nil.
 type ZcashAddress struct {
 	P2pkh   *[20]byte
@@ -27,47 +33,67 @@ type ZcashAddress struct {
 
 // A set of address prefix and lead-byte constants for a Zcash network.
 type Network struct {
-	p2pkhLead    [2]byte
-	p2shLead     [2]byte
-	texHRP       string
-	saplingHRP   string
-	unifiedHRP   string
-	unifiedR1HRP string
+	p2pkhLead     [2]byte
+	p2shLead      [2]byte
+	texHRP        string
+	saplingHRP    string
+	unifiedHRP    string
+	unifiedR1HRP  string
+	unifiedFVKHRP string
+	unifiedIVKHRP string
+}
+
+// UnifiedFVKHRP returns the human-readable part used for this network's
+// Unified Full Viewing Keys (see the unifiedviewingkey package).
+func (n Network) UnifiedFVKHRP() string {
+	return n.unifiedFVKHRP
+}
+
+// UnifiedIVKHRP returns the human-readable part used for this network's
+// Unified Incoming Viewing Keys (see the unifiedviewingkey package).
+func (n Network) UnifiedIVKHRP() string {
+	return n.unifiedIVKHRP
 }
 
 // The Zcash mainnet network constants.
 func Mainnet() Network {
 	return Network{
-		p2pkhLead:    [2]byte{0x1c, 0xb8},
-		p2shLead:     [2]byte{0x1c, 0xbd},
-		texHRP:       "tex",
-		saplingHRP:   "zs",
-		unifiedHRP:   "u",
-		unifiedR1HRP: "ur",
+		p2pkhLead:     [2]byte{0x1c, 0xb8},
+		p2shLead:      [2]byte{0x1c, 0xbd},
+		texHRP:        "tex",
+		saplingHRP:    "zs",
+		unifiedHRP:    "u",
+		unifiedR1HRP:  "ur",
+		unifiedFVKHRP: "uview",
+		unifiedIVKHRP: "uivk",
 	}
 }
 
 // The Zcash testnet network constants.
 func Testnet() Network {
 	return Network{
-		p2pkhLead:    [2]byte{0x1D, 0x25},
-		p2shLead:     [2]byte{0x1c, 0xba},
-		texHRP:       "textest",
-		saplingHRP:   "ztestsapling",
-		unifiedHRP:   "utest",
-		unifiedR1HRP: "urtest",
+		p2pkhLead:     [2]byte{0x1D, 0x25},
+		p2shLead:      [2]byte{0x1c, 0xba},
+		texHRP:        "textest",
+		saplingHRP:    "ztestsapling",
+		unifiedHRP:    "utest",
+		unifiedR1HRP:  "urtest",
+		unifiedFVKHRP: "uviewtest",
+		unifiedIVKHRP: "uivktest",
 	}
 }
 
 // The Zcash regtest network constants.
 func Regtest() Network {
 	return Network{
-		p2pkhLead:    [2]byte{0x1c, 0x25},
-		p2shLead:     [2]byte{0x1c, 0xba},
-		texHRP:       "texregtest",
-		saplingHRP:   "zregtestsapling",
-		unifiedHRP:   "uregtest",
-		unifiedR1HRP: "urregtest",
+		p2pkhLead:     [2]byte{0x1c, 0x25},
+		p2shLead:      [2]byte{0x1c, 0xba},
+		texHRP:        "texregtest",
+		saplingHRP:    "zregtestsapling",
+		unifiedHRP:    "uregtest",
+		unifiedR1HRP:  "urregtest",
+		unifiedFVKHRP: "uviewregtest",
+		unifiedIVKHRP: "uivkregtest",
 	}
 }
 
@@ -112,8 +138,13 @@ func DecodeAddress(address string, network Network) (result ZcashAddress, err er
 					return result, err
 				}
 			} else if humanReadablePrefix == network.unifiedR1HRP {
-				// attempt unified R1 decoding
-				return result, errors.New("unified address revision 1 decoding not yet supported")
+				// attempt unified address revision 1 decoding
+				unified, unifiedR1Err := unifiedaddress.DecodeUnifiedR1(address, network.unifiedR1HRP)
+				if unifiedR1Err != nil {
+					return result, unifiedR1Err
+				}
+				result.Unified = unified
+				return result, nil
 			}
 		} else if bech32Version == bech32.Version0 {
 			// this might be Sapling? Check for the "z" HRP
@@ -0,0 +1,20 @@
+package zcashaddress
+
+import "testing"
+
+// FuzzDecodeAddress asserts that DecodeAddress never panics on arbitrary
+// string input, across all three networks.
+func FuzzDecodeAddress(f *testing.F) {
+	f.Add("t1KFJ5p9SoXoZ1N3bpsm7bUYB1ioVhuJi2v")
+	f.Add("u1l8xunezsvhq8fr73m6u0sjv5fextt3wjxyx7qnmumqutstssn8z9lkljzgybq8u4qyvkzhmqxcvkvmx07phjwwmcjy7ew0lrnjqysx0ftd0xwpw3q9cgwkknxkpddmzyanpf5xganar6rvr6w")
+	f.Add("ur1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq")
+	f.Add("")
+	f.Add("tex1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq")
+	f.Add("zs1z7rejlpsa98s2rrrfkwmaxu53e4ue0ulcrw0h4x5g8jl04tak0d3mm47vdtahatqrlkngh9slya")
+
+	f.Fuzz(func(t *testing.T, address string) {
+		for _, network := range []Network{Mainnet(), Testnet(), Regtest()} {
+			_, _ = DecodeAddress(address, network)
+		}
+	})
+}